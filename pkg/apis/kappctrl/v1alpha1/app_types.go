@@ -0,0 +1,191 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// App describes a resource that maintains a running installation of
+// a templated and pushed set of resources against a cluster.
+type App struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppSpec   `json:"spec,omitempty"`
+	Status AppStatus `json:"status,omitempty"`
+}
+
+// AppSpec describes how App's template and deploy steps should be run.
+type AppSpec struct {
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	Fetch    []AppFetch    `json:"fetch,omitempty"`
+	Template []AppTemplate `json:"template,omitempty"`
+	Deploy   []AppDeploy   `json:"deploy,omitempty"`
+
+	Paused   bool `json:"paused,omitempty"`
+	Canceled bool `json:"canceled,omitempty"`
+
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	NoopDelete bool             `json:"noopDelete,omitempty"`
+
+	Cluster          *AppCluster `json:"cluster,omitempty"`
+	DefaultNamespace string      `json:"defaultNamespace,omitempty"`
+}
+
+// AppCluster specifies the cluster an App should be deployed into,
+// if not the cluster kapp-controller is running on.
+type AppCluster struct {
+	KubeconfigSecretRef *AppClusterKubeconfigSecretRef `json:"kubeconfigSecretRef,omitempty"`
+	Namespace           string                         `json:"namespace,omitempty"`
+}
+
+// AppClusterKubeconfigSecretRef points to a Secret containing a kubeconfig.
+type AppClusterKubeconfigSecretRef struct {
+	Name string `json:"name,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// AppFetch describes one step of retrieving source configuration.
+type AppFetch struct {
+	Inline       *AppFetchInline       `json:"inline,omitempty"`
+	Image        *AppFetchImage        `json:"image,omitempty"`
+	HTTP         *AppFetchHTTP         `json:"http,omitempty"`
+	Git          *AppFetchGit          `json:"git,omitempty"`
+	HelmChart    *AppFetchHelmChart    `json:"helmChart,omitempty"`
+	ImgpkgBundle *AppFetchImgpkgBundle `json:"imgpkgBundle,omitempty"`
+}
+
+// AppFetchLocalRef refers to a same-namespace Secret or ConfigMap by name.
+type AppFetchLocalRef struct {
+	Name string `json:"name,omitempty"`
+}
+
+// AppFetchInline specifies one or more files to use as-is.
+type AppFetchInline struct {
+	Paths     map[string]string      `json:"paths,omitempty"`
+	PathsFrom []AppFetchInlineSource `json:"pathsFrom,omitempty"`
+}
+
+// AppFetchInlineSource specifies where inline paths should be sourced from.
+// Exactly one of SecretRef or ConfigMapRef should be set.
+type AppFetchInlineSource struct {
+	SecretRef    *AppFetchInlineSourceRef `json:"secretRef,omitempty"`
+	ConfigMapRef *AppFetchInlineSourceRef `json:"configMapRef,omitempty"`
+}
+
+// AppFetchInlineSourceRef refers to a Secret or ConfigMap providing inline
+// paths.
+type AppFetchInlineSourceRef struct {
+	Name string `json:"name,omitempty"`
+	// +optional
+	DirectoryPath string `json:"directoryPath,omitempty"`
+}
+
+// AppFetchImage fetches content from an OCI image.
+type AppFetchImage struct {
+	URL       string            `json:"url,omitempty"`
+	SecretRef *AppFetchLocalRef `json:"secretRef,omitempty"`
+}
+
+// AppFetchHTTP fetches content from a URL.
+type AppFetchHTTP struct {
+	URL       string            `json:"url,omitempty"`
+	SecretRef *AppFetchLocalRef `json:"secretRef,omitempty"`
+}
+
+// AppFetchGit fetches content from a git repository.
+type AppFetchGit struct {
+	URL       string            `json:"url,omitempty"`
+	Ref       string            `json:"ref,omitempty"`
+	SecretRef *AppFetchLocalRef `json:"secretRef,omitempty"`
+}
+
+// AppFetchHelmChart fetches a Helm chart, optionally from a repository.
+type AppFetchHelmChart struct {
+	Name       string                 `json:"name,omitempty"`
+	Version    string                 `json:"version,omitempty"`
+	Repository *AppFetchHelmChartRepo `json:"repository,omitempty"`
+}
+
+// AppFetchHelmChartRepo describes the Helm repository a chart is fetched from.
+type AppFetchHelmChartRepo struct {
+	URL       string            `json:"url,omitempty"`
+	SecretRef *AppFetchLocalRef `json:"secretRef,omitempty"`
+}
+
+// AppFetchImgpkgBundle fetches content from an imgpkg bundle.
+type AppFetchImgpkgBundle struct {
+	Image     string            `json:"image,omitempty"`
+	SecretRef *AppFetchLocalRef `json:"secretRef,omitempty"`
+}
+
+// AppTemplate describes one step of templating fetched configuration.
+type AppTemplate struct {
+	Ytt          *AppTemplateYtt          `json:"ytt,omitempty"`
+	HelmTemplate *AppTemplateHelmTemplate `json:"helmTemplate,omitempty"`
+	Kbld         *AppTemplateKbld         `json:"kbld,omitempty"`
+}
+
+// AppTemplateYtt templates configuration with ytt.
+type AppTemplateYtt struct {
+	Paths      []string                  `json:"paths,omitempty"`
+	Inline     *AppFetchInline           `json:"inline,omitempty"`
+	ValuesFrom []AppTemplateValuesSource `json:"valuesFrom,omitempty"`
+}
+
+// AppTemplateHelmTemplate templates configuration with `helm template`.
+type AppTemplateHelmTemplate struct {
+	Name       string                    `json:"name,omitempty"`
+	Namespace  string                    `json:"namespace,omitempty"`
+	Path       string                    `json:"path,omitempty"`
+	ValuesFrom []AppTemplateValuesSource `json:"valuesFrom,omitempty"`
+}
+
+// AppTemplateKbld resolves images referenced by templated configuration.
+type AppTemplateKbld struct {
+	Paths []string `json:"paths,omitempty"`
+}
+
+// AppTemplateValuesSource specifies where template values come from.
+// Exactly one of SecretRef or ConfigMapRef should be set.
+type AppTemplateValuesSource struct {
+	SecretRef    *AppTemplateValuesSourceRef `json:"secretRef,omitempty"`
+	ConfigMapRef *AppTemplateValuesSourceRef `json:"configMapRef,omitempty"`
+}
+
+// AppTemplateValuesSourceRef refers to a Secret or ConfigMap providing
+// template values.
+type AppTemplateValuesSourceRef struct {
+	Name string `json:"name,omitempty"`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// AppDeploy describes one step of deploying templated configuration.
+type AppDeploy struct {
+	Kapp *AppDeployKapp `json:"kapp,omitempty"`
+}
+
+// AppDeployKapp deploys templated configuration with kapp.
+type AppDeployKapp struct {
+	RawOptions []string `json:"rawOptions,omitempty"`
+}
+
+// AppStatus records the observed state of an App.
+type AppStatus struct {
+	Conditions          []Condition `json:"conditions,omitempty"`
+	ObservedGeneration  int64       `json:"observedGeneration,omitempty"`
+	FriendlyDescription string      `json:"friendlyDescription,omitempty"`
+}
+
+// AppList is a list of App resources.
+type AppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []App `json:"items"`
+}
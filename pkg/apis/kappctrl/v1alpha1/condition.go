@@ -0,0 +1,28 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AppConditionType is the type of an App condition.
+type AppConditionType string
+
+const (
+	// ReconcileSucceeded indicates that the App was successfully reconciled.
+	ReconcileSucceeded AppConditionType = "ReconcileSucceeded"
+	// ReconcileFailed indicates that the App failed to reconcile.
+	ReconcileFailed AppConditionType = "ReconcileFailed"
+	// Reconciling indicates that the App reconciliation is in progress.
+	Reconciling AppConditionType = "Reconciling"
+)
+
+// Condition describes the state of an App at a certain point.
+type Condition struct {
+	Type    AppConditionType       `json:"type"`
+	Status  corev1.ConditionStatus `json:"status"`
+	Reason  string                 `json:"reason,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
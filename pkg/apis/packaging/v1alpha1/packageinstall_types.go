@@ -0,0 +1,95 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageInstall describes an installation of a Package into a cluster.
+type PackageInstall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PackageInstallSpec   `json:"spec,omitempty"`
+	Status PackageInstallStatus `json:"status,omitempty"`
+}
+
+// PackageInstallSpec describes the desired state of a PackageInstall.
+type PackageInstallSpec struct {
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	PackageRef *PackageRef `json:"packageRef,omitempty"`
+
+	Values []PackageInstallValues `json:"values,omitempty"`
+
+	Paused   bool `json:"paused,omitempty"`
+	Canceled bool `json:"canceled,omitempty"`
+
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	NoopDelete bool             `json:"noopDelete,omitempty"`
+
+	Cluster          *kcv1alpha1.AppCluster `json:"cluster,omitempty"`
+	DefaultNamespace string                 `json:"defaultNamespace,omitempty"`
+
+	// UpgradeConstraintPolicy controls whether kapp-controller allows the
+	// resolved package version to move backwards relative to the version
+	// currently installed. Defaults to Enforce.
+	// +optional
+	UpgradeConstraintPolicy UpgradeConstraintPolicy `json:"upgradeConstraintPolicy,omitempty"`
+}
+
+// UpgradeConstraintPolicy controls how downgrades are handled when
+// reconciling a PackageInstall against a resolved package version.
+type UpgradeConstraintPolicy string
+
+const (
+	// UpgradeConstraintPolicyEnforce rejects any transition to a package
+	// version lower than the one currently installed. This is the default.
+	UpgradeConstraintPolicyEnforce UpgradeConstraintPolicy = "Enforce"
+	// UpgradeConstraintPolicyIgnore allows PackageInstall to move to any
+	// resolved version, including ones lower than the one currently
+	// installed.
+	UpgradeConstraintPolicyIgnore UpgradeConstraintPolicy = "Ignore"
+)
+
+// PackageRef identifies a package by reference name and version selection.
+type PackageRef struct {
+	RefName string `json:"refName,omitempty"`
+}
+
+// PackageInstallValues specifies a single source of template values.
+// Exactly one of SecretRef or ConfigMapRef must be set: SecretRef for
+// sensitive values, ConfigMapRef for non-sensitive overrides (chart
+// defaults, feature flags) that do not need Secret-level protection.
+type PackageInstallValues struct {
+	SecretRef    *PackageInstallValuesSecretRef    `json:"secretRef,omitempty"`
+	ConfigMapRef *PackageInstallValuesConfigMapRef `json:"configMapRef,omitempty"`
+}
+
+// PackageInstallValuesSecretRef refers to a Secret containing values.
+type PackageInstallValuesSecretRef struct {
+	Name string `json:"name,omitempty"`
+}
+
+// PackageInstallValuesConfigMapRef refers to a ConfigMap containing values.
+type PackageInstallValuesConfigMapRef struct {
+	Name string `json:"name,omitempty"`
+}
+
+// PackageInstallStatus records the observed state of a PackageInstall.
+type PackageInstallStatus struct {
+	Conditions         []kcv1alpha1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+}
+
+// PackageInstallList is a list of PackageInstall resources.
+type PackageInstallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageInstall `json:"items"`
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageRepository describes a source of Package/PackageMetadata content
+// that kapp-controller fetches and unpacks into a namespace.
+type PackageRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PackageRepositorySpec   `json:"spec,omitempty"`
+	Status PackageRepositoryStatus `json:"status,omitempty"`
+}
+
+// PackageRepositorySpec describes how a PackageRepository's content is
+// fetched.
+type PackageRepositorySpec struct {
+	Fetch      *AppFetchSource  `json:"fetch,omitempty"`
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	Paused     bool             `json:"paused,omitempty"`
+}
+
+// AppFetchSource wraps the single AppFetch step used to retrieve a
+// PackageRepository's content, reusing the same fetch stanza App does.
+type AppFetchSource struct {
+	kcv1alpha1.AppFetch `json:",inline"`
+}
+
+// PackageRepositoryStatus reflects the result of the last reconciliation of
+// a PackageRepository.
+type PackageRepositoryStatus struct {
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+	Conditions         []kcv1alpha1.Condition `json:"conditions,omitempty"`
+}
+
+// PackageRepositoryList is a list of PackageRepository resources.
+type PackageRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageRepository `json:"items"`
+}
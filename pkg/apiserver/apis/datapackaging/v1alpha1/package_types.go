@@ -0,0 +1,40 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Package describes a single version of installable package content.
+type Package struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PackageSpec `json:"spec,omitempty"`
+}
+
+// PackageSpec describes how a Package's content is fetched, templated and
+// deployed once referenced by a PackageInstall.
+type PackageSpec struct {
+	RefName string `json:"refName,omitempty"`
+	Version string `json:"version,omitempty"`
+
+	Template AppTemplateSpec `json:"template,omitempty"`
+}
+
+// AppTemplateSpec wraps the AppSpec a Package resolves to.
+type AppTemplateSpec struct {
+	Spec *kcv1alpha1.AppSpec `json:"spec,omitempty"`
+}
+
+// PackageList is a list of Package resources.
+type PackageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Package `json:"items"`
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PackageMetadata describes the human-facing details shared by every
+// version of a package. Its name matches the RefName of the Packages it
+// describes, and it lives in the same namespace as those Packages.
+type PackageMetadata struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PackageMetadataSpec `json:"spec,omitempty"`
+}
+
+// PackageMetadataSpec describes a package for display purposes.
+type PackageMetadataSpec struct {
+	DisplayName      string `json:"displayName,omitempty"`
+	ShortDescription string `json:"shortDescription,omitempty"`
+	LongDescription  string `json:"longDescription,omitempty"`
+}
+
+// PackageMetadataList is a list of PackageMetadata resources.
+type PackageMetadataList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageMetadata `json:"items"`
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packagingaggregation
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Handler serves a Service's typed listing API over HTTP, so that external
+// dashboards and multi-cluster UIs can consume it without linking against
+// this package directly.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler wraps svc for registration on an http.ServeMux.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes mounts the Handler's list endpoints on mux under prefix,
+// e.g. prefix "/apis/packaging.carvel.dev/v1alpha1aggregated" yields
+// ".../packagerepositories", ".../packages" and ".../packageinstalls".
+func (h *Handler) RegisterRoutes(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc(prefix+"/packagerepositories", h.listPackageRepositories)
+	mux.HandleFunc(prefix+"/packages", h.listPackages)
+	mux.HandleFunc(prefix+"/packageinstalls", h.listPackageInstalls)
+}
+
+func (h *Handler) listPackageRepositories(w http.ResponseWriter, r *http.Request) {
+	result, err := h.svc.ListPackageRepositories(listOptionsFromRequest(r))
+	writeListResult(w, result, err)
+}
+
+func (h *Handler) listPackages(w http.ResponseWriter, r *http.Request) {
+	result, err := h.svc.ListPackages(listOptionsFromRequest(r))
+	writeListResult(w, result, err)
+}
+
+func (h *Handler) listPackageInstalls(w http.ResponseWriter, r *http.Request) {
+	result, err := h.svc.ListPackageInstalls(listOptionsFromRequest(r))
+	writeListResult(w, result, err)
+}
+
+func listOptionsFromRequest(r *http.Request) ListOptions {
+	query := r.URL.Query()
+	opts := ListOptions{
+		Namespace:     query.Get("namespace"),
+		LabelSelector: query.Get("labelSelector"),
+		Continue:      query.Get("continue"),
+	}
+	if limit, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil {
+		opts.Limit = limit
+	}
+	return opts
+}
+
+func writeListResult(w http.ResponseWriter, result interface{}, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packagingaggregation
+
+import (
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	pkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apis/packaging/v1alpha1"
+	datapkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apiserver/apis/datapackaging/v1alpha1"
+	datapkginglisters "carvel.dev/kapp-controller/pkg/client/listers/datapackaging/v1alpha1"
+	kcvlisters "carvel.dev/kapp-controller/pkg/client/listers/kappctrl/v1alpha1"
+	pkginglisters "carvel.dev/kapp-controller/pkg/client/listers/packaging/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NewPackageRepositoryLister adapts a generated, informer-backed
+// PackageRepositoryLister into the PackageRepositoryLister this package
+// needs, collapsing each PackageRepository into a PackageRepositoryView.
+func NewPackageRepositoryLister(lister pkginglisters.PackageRepositoryLister) PackageRepositoryLister {
+	return packageRepositoryListerAdapter{lister: lister}
+}
+
+type packageRepositoryListerAdapter struct {
+	lister pkginglisters.PackageRepositoryLister
+}
+
+func (a packageRepositoryListerAdapter) List(namespace string, selector labels.Selector) ([]PackageRepositoryView, error) {
+	repos, err := listNamespaced(namespace, selector, a.lister.List, func(ns string) func(labels.Selector) ([]*pkgingv1alpha1.PackageRepository, error) {
+		return a.lister.PackageRepositories(ns).List
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]PackageRepositoryView, 0, len(repos))
+	for _, repo := range repos {
+		items = append(items, PackageRepositoryView{
+			Name:      repo.Name,
+			Namespace: repo.Namespace,
+			Labels:    repo.Labels,
+			Status:    reconciliationStatusFromConditions(repo.Status.Conditions, repo.Status.ObservedGeneration),
+		})
+	}
+	return items, nil
+}
+
+// NewPackageLister adapts a generated, informer-backed PackageLister into
+// the PackageLister this package needs.
+func NewPackageLister(lister datapkginglisters.PackageLister) PackageLister {
+	return packageListerAdapter{lister: lister}
+}
+
+type packageListerAdapter struct {
+	lister datapkginglisters.PackageLister
+}
+
+func (a packageListerAdapter) List(namespace string, selector labels.Selector) ([]datapkgingv1alpha1.Package, error) {
+	pkgs, err := listNamespaced(namespace, selector, a.lister.List, func(ns string) func(labels.Selector) ([]*datapkgingv1alpha1.Package, error) {
+		return a.lister.Packages(ns).List
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]datapkgingv1alpha1.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		items = append(items, *pkg)
+	}
+	return items, nil
+}
+
+// NewPackageMetadataLister adapts a generated, informer-backed
+// PackageMetadataLister into the PackageMetadataLister this package needs.
+func NewPackageMetadataLister(lister datapkginglisters.PackageMetadataLister) PackageMetadataLister {
+	return packageMetadataListerAdapter{lister: lister}
+}
+
+type packageMetadataListerAdapter struct {
+	lister datapkginglisters.PackageMetadataLister
+}
+
+func (a packageMetadataListerAdapter) Get(namespace, name string) (*datapkgingv1alpha1.PackageMetadata, error) {
+	return a.lister.PackageMetadatas(namespace).Get(name)
+}
+
+// NewPackageInstallLister adapts a generated, informer-backed
+// PackageInstallLister into the PackageInstallLister this package needs.
+func NewPackageInstallLister(lister pkginglisters.PackageInstallLister) PackageInstallLister {
+	return packageInstallListerAdapter{lister: lister}
+}
+
+type packageInstallListerAdapter struct {
+	lister pkginglisters.PackageInstallLister
+}
+
+func (a packageInstallListerAdapter) List(namespace string, selector labels.Selector) ([]pkgingv1alpha1.PackageInstall, error) {
+	pkgInstalls, err := listNamespaced(namespace, selector, a.lister.List, func(ns string) func(labels.Selector) ([]*pkgingv1alpha1.PackageInstall, error) {
+		return a.lister.PackageInstalls(ns).List
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]pkgingv1alpha1.PackageInstall, 0, len(pkgInstalls))
+	for _, pkgInstall := range pkgInstalls {
+		items = append(items, *pkgInstall)
+	}
+	return items, nil
+}
+
+// NewAppLister adapts a generated, informer-backed AppLister into the
+// AppLister this package needs.
+func NewAppLister(lister kcvlisters.AppLister) AppLister {
+	return appListerAdapter{lister: lister}
+}
+
+type appListerAdapter struct {
+	lister kcvlisters.AppLister
+}
+
+func (a appListerAdapter) Get(namespace, name string) (*kcv1alpha1.App, error) {
+	return a.lister.Apps(namespace).Get(name)
+}
+
+// listNamespaced dispatches to allNamespaces when namespace is empty (the
+// "list across the whole cluster" case every generated lister supports),
+// and to namespaced(namespace) otherwise.
+func listNamespaced[T any](namespace string, selector labels.Selector, allNamespaces func(labels.Selector) ([]T, error), namespaced func(string) func(labels.Selector) ([]T, error)) ([]T, error) {
+	if namespace == "" {
+		return allNamespaces(selector)
+	}
+	return namespaced(namespace)(selector)
+}
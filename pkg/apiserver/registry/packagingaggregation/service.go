@@ -0,0 +1,274 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packagingaggregation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	pkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apis/packaging/v1alpha1"
+	datapkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apiserver/apis/datapackaging/v1alpha1"
+	"carvel.dev/kapp-controller/pkg/packageinstall"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PackageRepositoryLister is the read-only view of PackageRepositories this
+// package needs. NewPackageRepositoryLister builds one from a generated
+// lister backed by an informer cache.
+type PackageRepositoryLister interface {
+	List(namespace string, selector labels.Selector) ([]PackageRepositoryView, error)
+}
+
+// PackageLister is the read-only view of Packages this package needs.
+type PackageLister interface {
+	List(namespace string, selector labels.Selector) ([]datapkgingv1alpha1.Package, error)
+}
+
+// PackageMetadataLister is the read-only view of PackageMetadata this
+// package needs, used to resolve a Package's human-readable DisplayName.
+// A PackageMetadata's name matches the RefName of the Packages it
+// describes, and it lives in the same namespace as those Packages.
+type PackageMetadataLister interface {
+	Get(namespace, name string) (*datapkgingv1alpha1.PackageMetadata, error)
+}
+
+// PackageInstallLister is the read-only view of PackageInstalls this
+// package needs.
+type PackageInstallLister interface {
+	List(namespace string, selector labels.Selector) ([]pkgingv1alpha1.PackageInstall, error)
+}
+
+// AppLister is the read-only view of Apps this package needs, used to join
+// a PackageInstall with the reconciliation state it drives.
+type AppLister interface {
+	Get(namespace, name string) (*kcv1alpha1.App, error)
+}
+
+// Service implements the typed listing API described in the package doc
+// comment, on top of listers backed by informer caches.
+type Service struct {
+	packageRepositories PackageRepositoryLister
+	packages            PackageLister
+	packageMetadata     PackageMetadataLister
+	packageInstalls     PackageInstallLister
+	apps                AppLister
+}
+
+// NewService builds a Service from the listers/informers already
+// maintained by the controller.
+func NewService(packageRepositories PackageRepositoryLister, packages PackageLister, packageMetadata PackageMetadataLister, packageInstalls PackageInstallLister, apps AppLister) *Service {
+	return &Service{
+		packageRepositories: packageRepositories,
+		packages:            packages,
+		packageMetadata:     packageMetadata,
+		packageInstalls:     packageInstalls,
+		apps:                apps,
+	}
+}
+
+// ListPackageRepositories returns a page of PackageRepositories matching
+// opts.
+func (s *Service) ListPackageRepositories(opts ListOptions) (*PackageRepositoryList, error) {
+	selector, err := parseSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.packageRepositories.List(opts.Namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("Listing package repositories: %s", err)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return lessByNamespacedName(items[i].Namespace, items[i].Name, items[j].Namespace, items[j].Name)
+	})
+
+	page, cont, remaining, err := paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageRepositoryList{ListMeta: ListMeta{Continue: cont, RemainingItemCount: remaining}, Items: page}, nil
+}
+
+// ListPackages returns a page of Packages matching opts, collapsed into
+// PackageViews.
+func (s *Service) ListPackages(opts ListOptions) (*PackageList, error) {
+	selector, err := parseSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := s.packages.List(opts.Namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("Listing packages: %s", err)
+	}
+
+	items := make([]PackageView, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		view := PackageView{
+			Name:      pkg.Name,
+			Namespace: pkg.Namespace,
+			Labels:    pkg.Labels,
+			RefName:   pkg.Spec.RefName,
+			Version:   pkg.Spec.Version,
+		}
+		if s.packageMetadata != nil {
+			if meta, err := s.packageMetadata.Get(pkg.Namespace, pkg.Spec.RefName); err == nil && meta != nil {
+				view.DisplayName = meta.Spec.DisplayName
+			}
+		}
+		items = append(items, view)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return lessByNamespacedName(items[i].Namespace, items[i].Name, items[j].Namespace, items[j].Name)
+	})
+
+	page, cont, remaining, err := paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageList{ListMeta: ListMeta{Continue: cont, RemainingItemCount: remaining}, Items: page}, nil
+}
+
+// ListPackageInstalls returns a page of PackageInstalls matching opts,
+// joined with the App each one drives.
+func (s *Service) ListPackageInstalls(opts ListOptions) (*PackageInstallList, error) {
+	selector, err := parseSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgInstalls, err := s.packageInstalls.List(opts.Namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("Listing package installs: %s", err)
+	}
+
+	items := make([]PackageInstallView, 0, len(pkgInstalls))
+	for _, pkgInstall := range pkgInstalls {
+		items = append(items, s.viewForPackageInstall(pkgInstall))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return lessByNamespacedName(items[i].Namespace, items[i].Name, items[j].Namespace, items[j].Name)
+	})
+
+	page, cont, remaining, err := paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageInstallList{ListMeta: ListMeta{Continue: cont, RemainingItemCount: remaining}, Items: page}, nil
+}
+
+func (s *Service) viewForPackageInstall(pkgInstall pkgingv1alpha1.PackageInstall) PackageInstallView {
+	view := PackageInstallView{
+		Name:                    pkgInstall.Name,
+		Namespace:               pkgInstall.Namespace,
+		Labels:                  pkgInstall.Labels,
+		UpgradeConstraintPolicy: string(pkgInstall.Spec.UpgradeConstraintPolicy),
+	}
+	if pkgInstall.Spec.PackageRef != nil {
+		view.PackageRefName = pkgInstall.Spec.PackageRef.RefName
+	}
+
+	for _, secretName := range packageinstall.FetchSecretNamesFromAnn(&pkgInstall) {
+		view.FetchValuesSources = append(view.FetchValuesSources, ValuesSource{Kind: ValuesSourceKindSecret, Name: secretName})
+	}
+
+	for _, value := range pkgInstall.Spec.Values {
+		switch {
+		case value.SecretRef != nil:
+			view.TemplateValuesSources = append(view.TemplateValuesSources, ValuesSource{Kind: ValuesSourceKindSecret, Name: value.SecretRef.Name})
+		case value.ConfigMapRef != nil:
+			view.TemplateValuesSources = append(view.TemplateValuesSources, ValuesSource{Kind: ValuesSourceKindConfigMap, Name: value.ConfigMapRef.Name})
+		}
+	}
+	for _, secretName := range packageinstall.SecretNamesFromAnn(&pkgInstall, packageinstall.ExtYttPathsFromSecretNameAnnKey) {
+		view.TemplateValuesSources = append(view.TemplateValuesSources, ValuesSource{Kind: ValuesSourceKindSecret, Name: secretName})
+	}
+	for _, secretName := range packageinstall.SecretNamesFromAnn(&pkgInstall, packageinstall.ExtHelmPathsFromSecretNameAnnKey) {
+		view.TemplateValuesSources = append(view.TemplateValuesSources, ValuesSource{Kind: ValuesSourceKindSecret, Name: secretName})
+	}
+
+	if app, err := s.apps.Get(pkgInstall.Namespace, pkgInstall.Name); err == nil && app != nil {
+		view.InstalledVersion = app.Annotations[packageinstall.InstalledBundleVersionAnnKey]
+		view.Status = reconciliationStatusFromConditions(app.Status.Conditions, app.Status.ObservedGeneration)
+	}
+
+	return view
+}
+
+// reconciliationStatusFromConditions collapses the kappctrl reconciliation
+// conditions shared by App and PackageRepository into a single
+// ReconciliationStatus.
+func reconciliationStatusFromConditions(conditions []kcv1alpha1.Condition, observedGeneration int64) ReconciliationStatus {
+	status := ReconciliationStatus{State: "Unknown", ObservedGeneration: observedGeneration}
+
+	for _, cond := range conditions {
+		switch cond.Type {
+		case kcv1alpha1.ReconcileSucceeded, kcv1alpha1.ReconcileFailed, kcv1alpha1.Reconciling:
+			status.State = string(cond.Type)
+			status.Message = cond.Message
+		}
+	}
+
+	return status
+}
+
+func parseSelector(raw string) (labels.Selector, error) {
+	if raw == "" {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing label selector '%s': %s", raw, err)
+	}
+	return selector, nil
+}
+
+func lessByNamespacedName(ns1, n1, ns2, n2 string) bool {
+	if ns1 != ns2 {
+		return ns1 < ns2
+	}
+	return n1 < n2
+}
+
+// paginate slices items starting at opts.Continue (an index encoded as a
+// decimal string) and returns at most opts.Limit of them, along with the
+// continue token for the next page and how many items remain after it.
+func paginate[T any](items []T, opts ListOptions) ([]T, string, *int64, error) {
+	start := 0
+	if opts.Continue != "" {
+		parsed, err := strconv.Atoi(opts.Continue)
+		if err != nil || parsed < 0 {
+			return nil, "", nil, fmt.Errorf("Invalid continue token '%s'", opts.Continue)
+		}
+		start = parsed
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := len(items)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+	}
+
+	page := items[start:end]
+
+	var cont string
+	var remaining *int64
+	if end < len(items) {
+		cont = strconv.Itoa(end)
+		r := int64(len(items) - end)
+		remaining = &r
+	}
+
+	return page, cont, remaining, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packagingaggregation_test
+
+import (
+	"fmt"
+	"testing"
+
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	pkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apis/packaging/v1alpha1"
+	datapkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apiserver/apis/datapackaging/v1alpha1"
+	"carvel.dev/kapp-controller/pkg/apiserver/registry/packagingaggregation"
+	"carvel.dev/kapp-controller/pkg/packageinstall"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakePackageInstallLister struct {
+	items []pkgingv1alpha1.PackageInstall
+}
+
+func (f fakePackageInstallLister) List(namespace string, selector labels.Selector) ([]pkgingv1alpha1.PackageInstall, error) {
+	var out []pkgingv1alpha1.PackageInstall
+	for _, item := range f.items {
+		if namespace != "" && item.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(item.Labels)) {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+type fakePackageRepositoryLister struct{}
+
+func (fakePackageRepositoryLister) List(string, labels.Selector) ([]packagingaggregation.PackageRepositoryView, error) {
+	return nil, nil
+}
+
+type fakePackageLister struct{ items []datapkgingv1alpha1.Package }
+
+func (f fakePackageLister) List(namespace string, selector labels.Selector) ([]datapkgingv1alpha1.Package, error) {
+	var out []datapkgingv1alpha1.Package
+	for _, item := range f.items {
+		if namespace != "" && item.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(item.Labels)) {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+type fakePackageMetadataLister struct {
+	items map[string]*datapkgingv1alpha1.PackageMetadata
+}
+
+func (f fakePackageMetadataLister) Get(namespace, name string) (*datapkgingv1alpha1.PackageMetadata, error) {
+	meta, found := f.items[namespace+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("not found")
+	}
+	return meta, nil
+}
+
+type fakeAppLister struct{ apps map[string]*kcv1alpha1.App }
+
+func (f fakeAppLister) Get(namespace, name string) (*kcv1alpha1.App, error) {
+	app, found := f.apps[namespace+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("not found")
+	}
+	return app, nil
+}
+
+func TestListPackageInstallsCollapsesExtAnnotations(t *testing.T) {
+	pkgInstall := pkgingv1alpha1.PackageInstall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app1",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				fmt.Sprintf(packageinstall.ExtFetchSecretNameAnnKeyFmt, 0): "fetch-secret",
+				packageinstall.ExtYttPathsFromSecretNameAnnKey:             "ytt-secret",
+			},
+		},
+		Spec: pkgingv1alpha1.PackageInstallSpec{
+			PackageRef: &pkgingv1alpha1.PackageRef{RefName: "pkg.test.carvel.dev"},
+			Values: []pkgingv1alpha1.PackageInstallValues{
+				{ConfigMapRef: &pkgingv1alpha1.PackageInstallValuesConfigMapRef{Name: "cm1"}},
+			},
+		},
+	}
+
+	app := &kcv1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app1",
+			Namespace:   "ns1",
+			Annotations: map[string]string{packageinstall.InstalledBundleVersionAnnKey: "1.0.0"},
+		},
+		Status: kcv1alpha1.AppStatus{
+			Conditions: []kcv1alpha1.Condition{{Type: kcv1alpha1.ReconcileSucceeded}},
+		},
+	}
+
+	svc := packagingaggregation.NewService(
+		fakePackageRepositoryLister{},
+		fakePackageLister{},
+		fakePackageMetadataLister{},
+		fakePackageInstallLister{items: []pkgingv1alpha1.PackageInstall{pkgInstall}},
+		fakeAppLister{apps: map[string]*kcv1alpha1.App{"ns1/app1": app}},
+	)
+
+	result, err := svc.ListPackageInstalls(packagingaggregation.ListOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(result.Items))
+	}
+
+	view := result.Items[0]
+	if view.InstalledVersion != "1.0.0" {
+		t.Errorf("expected installed version '1.0.0', got '%s'", view.InstalledVersion)
+	}
+	if view.Status.State != string(kcv1alpha1.ReconcileSucceeded) {
+		t.Errorf("expected status '%s', got '%s'", kcv1alpha1.ReconcileSucceeded, view.Status.State)
+	}
+	if len(view.FetchValuesSources) != 1 || view.FetchValuesSources[0].Name != "fetch-secret" {
+		t.Errorf("expected a single fetch values source 'fetch-secret', got: %+v", view.FetchValuesSources)
+	}
+
+	foundConfigMap := false
+	foundYttSecret := false
+	for _, src := range view.TemplateValuesSources {
+		if src.Kind == packagingaggregation.ValuesSourceKindConfigMap && src.Name == "cm1" {
+			foundConfigMap = true
+		}
+		if src.Kind == packagingaggregation.ValuesSourceKindSecret && src.Name == "ytt-secret" {
+			foundYttSecret = true
+		}
+	}
+	if !foundConfigMap {
+		t.Errorf("expected template values sources to include configMap 'cm1', got: %+v", view.TemplateValuesSources)
+	}
+	if !foundYttSecret {
+		t.Errorf("expected template values sources to include secret 'ytt-secret', got: %+v", view.TemplateValuesSources)
+	}
+}
+
+func TestListPackageInstallsPagination(t *testing.T) {
+	var items []pkgingv1alpha1.PackageInstall
+	for i := 0; i < 5; i++ {
+		items = append(items, pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app%d", i), Namespace: "ns1"},
+		})
+	}
+
+	svc := packagingaggregation.NewService(
+		fakePackageRepositoryLister{},
+		fakePackageLister{},
+		fakePackageMetadataLister{},
+		fakePackageInstallLister{items: items},
+		fakeAppLister{apps: map[string]*kcv1alpha1.App{}},
+	)
+
+	firstPage, err := svc.ListPackageInstalls(packagingaggregation.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if len(firstPage.Items) != 2 || firstPage.Continue == "" {
+		t.Fatalf("expected a 2-item page with a continue token, got %d items, continue=%q", len(firstPage.Items), firstPage.Continue)
+	}
+
+	secondPage, err := svc.ListPackageInstalls(packagingaggregation.ListOptions{Limit: 2, Continue: firstPage.Continue})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if len(secondPage.Items) != 2 {
+		t.Fatalf("expected a 2-item second page, got %d", len(secondPage.Items))
+	}
+	if secondPage.Items[0].Name == firstPage.Items[0].Name {
+		t.Fatalf("expected second page to start after the first page")
+	}
+}
+
+func TestListPackagesPopulatesDisplayNameFromMetadata(t *testing.T) {
+	pkg := datapkgingv1alpha1.Package{
+		ObjectMeta: metav1.ObjectMeta{Name: "pkg.test.carvel.dev.1.0.0", Namespace: "ns1"},
+		Spec:       datapkgingv1alpha1.PackageSpec{RefName: "pkg.test.carvel.dev", Version: "1.0.0"},
+	}
+	meta := &datapkgingv1alpha1.PackageMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: "pkg.test.carvel.dev", Namespace: "ns1"},
+		Spec:       datapkgingv1alpha1.PackageMetadataSpec{DisplayName: "Test Package"},
+	}
+
+	svc := packagingaggregation.NewService(
+		fakePackageRepositoryLister{},
+		fakePackageLister{items: []datapkgingv1alpha1.Package{pkg}},
+		fakePackageMetadataLister{items: map[string]*datapkgingv1alpha1.PackageMetadata{"ns1/pkg.test.carvel.dev": meta}},
+		fakePackageInstallLister{},
+		fakeAppLister{apps: map[string]*kcv1alpha1.App{}},
+	)
+
+	result, err := svc.ListPackages(packagingaggregation.ListOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(result.Items))
+	}
+	if result.Items[0].DisplayName != "Test Package" {
+		t.Errorf("expected display name 'Test Package', got '%s'", result.Items[0].DisplayName)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package packagingaggregation exposes a stable, typed read API over
+// PackageRepository, Package/PackageMetadata and PackageInstall+App state
+// across namespaces. Service is built from listers backed by the
+// controller's own informer caches (see NewPackageRepositoryLister et al.
+// in informers.go) and Handler serves it over HTTP (see RegisterRoutes in
+// handler.go), so that external UIs and multi-cluster dashboards do not
+// need to reimplement the ext.* annotation parsing that
+// packageinstall.NewApp does on the controller's behalf.
+package packagingaggregation
+
+// ValuesSourceKind identifies the kind of object a ValuesSource points to.
+type ValuesSourceKind string
+
+const (
+	// ValuesSourceKindSecret indicates a ValuesSource backed by a Secret.
+	ValuesSourceKindSecret ValuesSourceKind = "Secret"
+	// ValuesSourceKindConfigMap indicates a ValuesSource backed by a
+	// ConfigMap.
+	ValuesSourceKindConfigMap ValuesSourceKind = "ConfigMap"
+)
+
+// ValuesSource is a typed, collapsed view of a single source of template
+// values or fetch credentials, regardless of whether it came from
+// spec.values[], an ext.* annotation, or a ytt/helm overlay.
+type ValuesSource struct {
+	Kind ValuesSourceKind `json:"kind"`
+	Name string           `json:"name"`
+}
+
+// ReconciliationStatus mirrors the subset of App.Status.Conditions that
+// matters to a dashboard: whether the latest reconcile succeeded, is in
+// progress, or failed, and why.
+type ReconciliationStatus struct {
+	State              string `json:"state"`
+	Message            string `json:"message,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
+// PackageRepositoryView is a typed, namespace-scoped view of a
+// PackageRepository.
+type PackageRepositoryView struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	Status ReconciliationStatus `json:"status"`
+}
+
+// PackageView is a typed view of a single Package/PackageMetadata version
+// available for install.
+type PackageView struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	RefName     string            `json:"refName"`
+	Version     string            `json:"version"`
+	DisplayName string            `json:"displayName,omitempty"`
+}
+
+// PackageInstallView is a typed view of a PackageInstall joined with the
+// App it drives, collapsing the ext.* annotation model into first-class
+// fields.
+type PackageInstallView struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	PackageRefName          string `json:"packageRefName"`
+	InstalledVersion        string `json:"installedVersion,omitempty"`
+	UpgradeConstraintPolicy string `json:"upgradeConstraintPolicy,omitempty"`
+
+	FetchValuesSources    []ValuesSource `json:"fetchValuesSources,omitempty"`
+	TemplateValuesSources []ValuesSource `json:"templateValuesSources,omitempty"`
+
+	Status ReconciliationStatus `json:"status"`
+}
+
+// ListOptions controls pagination and filtering of List calls.
+type ListOptions struct {
+	Namespace     string
+	LabelSelector string
+	// Limit is the maximum number of items to return. A value <= 0 means
+	// no limit.
+	Limit int64
+	// Continue is an opaque token returned by a previous List call's
+	// ListMeta.Continue; pass it back to fetch the next page.
+	Continue string
+}
+
+// ListMeta is returned alongside each page of results.
+type ListMeta struct {
+	// Continue is non-empty when more results are available; pass it back
+	// as ListOptions.Continue to fetch the next page.
+	Continue string `json:"continue,omitempty"`
+	// RemainingItemCount is a best-effort count of items left after this
+	// page, when known.
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+}
+
+// PackageRepositoryList is a page of PackageRepositoryView results.
+type PackageRepositoryList struct {
+	ListMeta
+	Items []PackageRepositoryView `json:"items"`
+}
+
+// PackageList is a page of PackageView results.
+type PackageList struct {
+	ListMeta
+	Items []PackageView `json:"items"`
+}
+
+// PackageInstallList is a page of PackageInstallView results.
+type PackageInstallList struct {
+	ListMeta
+	Items []PackageInstallView `json:"items"`
+}
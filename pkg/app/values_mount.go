@@ -0,0 +1,153 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MountInlineValuesSource fetches the Secret or ConfigMap referenced by
+// src and writes each of its keys as a file under dstDir, so that ytt's
+// inline PathsFrom sees ConfigMap-sourced values on disk exactly the same
+// way it already sees SecretRef-sourced ones.
+func MountInlineValuesSource(ctx context.Context, coreClient kubernetes.Interface, namespace string, src kcv1alpha1.AppFetchInlineSource, dstDir string) error {
+	switch {
+	case src.SecretRef != nil && src.ConfigMapRef == nil:
+		secret, err := coreClient.CoreV1().Secrets(namespace).Get(ctx, src.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Fetching secret '%s/%s' for inline values: %s", namespace, src.SecretRef.Name, err)
+		}
+		data := map[string][]byte(secret.Data)
+		return writeValuesFiles(filepath.Join(dstDir, src.SecretRef.DirectoryPath), data)
+
+	case src.ConfigMapRef != nil && src.SecretRef == nil:
+		configMap, err := coreClient.CoreV1().ConfigMaps(namespace).Get(ctx, src.ConfigMapRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Fetching configmap '%s/%s' for inline values: %s", namespace, src.ConfigMapRef.Name, err)
+		}
+		data := map[string][]byte{}
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+		return writeValuesFiles(filepath.Join(dstDir, src.ConfigMapRef.DirectoryPath), data)
+
+	default:
+		return fmt.Errorf("Expected exactly one of secretRef or configMapRef to be set")
+	}
+}
+
+// MountTemplateValuesSource fetches the Secret or ConfigMap referenced by
+// src and returns the path to a ytt data values file built from it, for
+// use alongside ytt's `-f`/`--data-values-file` flags. ConfigMap-sourced
+// values are mounted the same way SecretRef-sourced ones already are.
+func MountTemplateValuesSource(ctx context.Context, coreClient kubernetes.Interface, namespace string, src kcv1alpha1.AppTemplateValuesSource, dstDir string) (string, error) {
+	switch {
+	case src.SecretRef != nil && src.ConfigMapRef == nil:
+		secret, err := coreClient.CoreV1().Secrets(namespace).Get(ctx, src.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("Fetching secret '%s/%s' for template values: %s", namespace, src.SecretRef.Name, err)
+		}
+		return writeValuesFile(dstDir, src.SecretRef.Name, valuesKey(secret.Data, src.SecretRef.Path))
+
+	case src.ConfigMapRef != nil && src.SecretRef == nil:
+		configMap, err := coreClient.CoreV1().ConfigMaps(namespace).Get(ctx, src.ConfigMapRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("Fetching configmap '%s/%s' for template values: %s", namespace, src.ConfigMapRef.Name, err)
+		}
+		data := map[string][]byte{}
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+		return writeValuesFile(dstDir, src.ConfigMapRef.Name, valuesKey(data, src.ConfigMapRef.Path))
+
+	default:
+		return "", fmt.Errorf("Expected exactly one of secretRef or configMapRef to be set")
+	}
+}
+
+// MountAppValuesSources mounts every values source referenced by app's
+// fetch and template steps under workingDir: it turns SecretRef/ConfigMapRef
+// values into files on disk, for both the fetch-time Inline.PathsFrom
+// sources MountInlineValuesSource handles and the template-time ValuesFrom
+// sources MountTemplateValuesSource handles. Inline.PathsFrom sources on
+// ytt template steps are not mounted. It returns the data values
+// file paths produced for each template step, keyed by step index, for
+// the exec step to pass to ytt/helm via `-f`/`--values`.
+func MountAppValuesSources(ctx context.Context, coreClient kubernetes.Interface, app *kcv1alpha1.App, workingDir string) (map[int][]string, error) {
+	for i, fetchStep := range app.Spec.Fetch {
+		if fetchStep.Inline == nil {
+			continue
+		}
+		dstDir := filepath.Join(workingDir, fmt.Sprintf("fetch-%d", i))
+		for _, src := range fetchStep.Inline.PathsFrom {
+			if err := MountInlineValuesSource(ctx, coreClient, app.Namespace, src, dstDir); err != nil {
+				return nil, fmt.Errorf("Mounting values for fetch step %d: %s", i, err)
+			}
+		}
+	}
+
+	templateValuesFilePaths := map[int][]string{}
+	for i, templateStep := range app.Spec.Template {
+		var valuesFrom []kcv1alpha1.AppTemplateValuesSource
+		switch {
+		case templateStep.Ytt != nil:
+			valuesFrom = templateStep.Ytt.ValuesFrom
+		case templateStep.HelmTemplate != nil:
+			valuesFrom = templateStep.HelmTemplate.ValuesFrom
+		default:
+			continue
+		}
+
+		dstDir := filepath.Join(workingDir, fmt.Sprintf("template-%d", i))
+		for _, src := range valuesFrom {
+			path, err := MountTemplateValuesSource(ctx, coreClient, app.Namespace, src, dstDir)
+			if err != nil {
+				return nil, fmt.Errorf("Mounting values for template step %d: %s", i, err)
+			}
+			templateValuesFilePaths[i] = append(templateValuesFilePaths[i], path)
+		}
+	}
+
+	return templateValuesFilePaths, nil
+}
+
+func valuesKey(data map[string][]byte, path string) []byte {
+	if path != "" {
+		return data[path]
+	}
+	for _, value := range data {
+		return value
+	}
+	return nil
+}
+
+func writeValuesFiles(dstDir string, data map[string][]byte) error {
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return fmt.Errorf("Creating directory '%s': %s", dstDir, err)
+	}
+	for key, value := range data {
+		if err := os.WriteFile(filepath.Join(dstDir, key), value, 0600); err != nil {
+			return fmt.Errorf("Writing file '%s': %s", key, err)
+		}
+	}
+	return nil
+}
+
+func writeValuesFile(dstDir, name string, value []byte) (string, error) {
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return "", fmt.Errorf("Creating directory '%s': %s", dstDir, err)
+	}
+	path := filepath.Join(dstDir, name)
+	if err := os.WriteFile(path, value, 0600); err != nil {
+		return "", fmt.Errorf("Writing file '%s': %s", path, err)
+	}
+	return path, nil
+}
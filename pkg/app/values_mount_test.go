@@ -0,0 +1,133 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kcv1alpha1 "carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	"carvel.dev/kapp-controller/pkg/app"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMountInlineValuesSourceConfigMapRef(t *testing.T) {
+	coreClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "ns1"},
+		Data:       map[string]string{"values.yml": "foo: bar"},
+	})
+
+	dstDir := t.TempDir()
+
+	err := app.MountInlineValuesSource(context.Background(), coreClient, "ns1", kcv1alpha1.AppFetchInlineSource{
+		ConfigMapRef: &kcv1alpha1.AppFetchInlineSourceRef{Name: "cm1"},
+	}, dstDir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dstDir, "values.yml"))
+	if err != nil {
+		t.Fatalf("expected values.yml to be mounted, got: %s", err)
+	}
+	if string(contents) != "foo: bar" {
+		t.Errorf("expected mounted file content 'foo: bar', got '%s'", contents)
+	}
+}
+
+func TestMountTemplateValuesSourceConfigMapRef(t *testing.T) {
+	coreClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "ns1"},
+		Data:       map[string]string{"values.yml": "foo: bar"},
+	})
+
+	dstDir := t.TempDir()
+
+	path, err := app.MountTemplateValuesSource(context.Background(), coreClient, "ns1", kcv1alpha1.AppTemplateValuesSource{
+		ConfigMapRef: &kcv1alpha1.AppTemplateValuesSourceRef{Name: "cm1", Path: "values.yml"},
+	}, dstDir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected values file to be written, got: %s", err)
+	}
+	if string(contents) != "foo: bar" {
+		t.Errorf("expected mounted file content 'foo: bar', got '%s'", contents)
+	}
+}
+
+func TestMountInlineValuesSourceRejectsBothRefsSet(t *testing.T) {
+	coreClient := fake.NewSimpleClientset()
+
+	err := app.MountInlineValuesSource(context.Background(), coreClient, "ns1", kcv1alpha1.AppFetchInlineSource{
+		SecretRef:    &kcv1alpha1.AppFetchInlineSourceRef{Name: "s1"},
+		ConfigMapRef: &kcv1alpha1.AppFetchInlineSourceRef{Name: "cm1"},
+	}, t.TempDir())
+	if err == nil {
+		t.Fatalf("expected a validation error, got none")
+	}
+}
+
+func TestMountAppValuesSourcesMountsFetchAndTemplateSteps(t *testing.T) {
+	coreClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "fetch-secret", Namespace: "ns1"},
+			Data:       map[string][]byte{"values.yml": []byte("fetch: true")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "template-cm", Namespace: "ns1"},
+			Data:       map[string]string{"values.yml": "template: true"},
+		},
+	)
+
+	testApp := &kcv1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1"},
+		Spec: kcv1alpha1.AppSpec{
+			Fetch: []kcv1alpha1.AppFetch{{
+				Inline: &kcv1alpha1.AppFetchInline{
+					PathsFrom: []kcv1alpha1.AppFetchInlineSource{{SecretRef: &kcv1alpha1.AppFetchInlineSourceRef{Name: "fetch-secret"}}},
+				},
+			}},
+			Template: []kcv1alpha1.AppTemplate{{
+				Ytt: &kcv1alpha1.AppTemplateYtt{
+					ValuesFrom: []kcv1alpha1.AppTemplateValuesSource{{ConfigMapRef: &kcv1alpha1.AppTemplateValuesSourceRef{Name: "template-cm", Path: "values.yml"}}},
+				},
+			}},
+		},
+	}
+
+	workingDir := t.TempDir()
+
+	templateValuesFilePaths, err := app.MountAppValuesSources(context.Background(), coreClient, testApp, workingDir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(workingDir, "fetch-0", "values.yml"))
+	if err != nil {
+		t.Fatalf("expected fetch step values to be mounted, got: %s", err)
+	}
+	if string(contents) != "fetch: true" {
+		t.Errorf("expected mounted fetch values 'fetch: true', got '%s'", contents)
+	}
+
+	paths, found := templateValuesFilePaths[0]
+	if !found || len(paths) != 1 {
+		t.Fatalf("expected one template values file path for template step 0, got: %+v", templateValuesFilePaths)
+	}
+	contents, err = os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("expected template step values file to exist, got: %s", err)
+	}
+	if string(contents) != "template: true" {
+		t.Errorf("expected mounted template values 'template: true', got '%s'", contents)
+	}
+}
@@ -5,7 +5,9 @@ package packageinstall
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
@@ -13,11 +15,20 @@ import (
 	pkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apis/packaging/v1alpha1"
 	datapkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apiserver/apis/datapackaging/v1alpha1"
 	"carvel.dev/kapp-controller/pkg/client/clientset/versioned/scheme"
+	"carvel.dev/kapp-controller/pkg/packageinstall/pathresolver"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
+	// ManuallyControlledAnnKey, when present on the existing App, freezes
+	// reconciliation of the fields it names against further changes from
+	// the PackageInstall/Package. Its value is a comma-separated list of
+	// dotted/indexed paths into the App (e.g.
+	// "spec.syncPeriod,spec.fetch[0].image.secretRef"); each named field is
+	// restored from the existing App after NewApp otherwise computes the
+	// desired App. An empty value freezes every field, matching the
+	// previous all-or-nothing behavior of this annotation.
 	ManuallyControlledAnnKey = "ext.packaging.carvel.dev/manually-controlled"
 
 	HelmTemplateOverlayNameKey      = "ext.packaging.carvel.dev/helm-template-name"
@@ -30,6 +41,13 @@ const (
 	ExtYttDataValuesOverlaysAnnKey = "ext.packaging.carvel.dev/ytt-data-values-overlays"
 
 	ExtFetchSecretNameAnnKeyFmt = "ext.packaging.carvel.dev/fetch-%d-secret-name"
+
+	// InstalledBundleVersionAnnKey records, on the App that NewApp returns,
+	// the package version NewApp resolved for it. On the next call NewApp
+	// reads it back from the existing App and, unless the PackageInstall's
+	// UpgradeConstraintPolicy is Ignore, rejects a resolved version lower
+	// than the recorded one.
+	InstalledBundleVersionAnnKey = "packaging.carvel.dev/installed-bundle-version"
 )
 
 // NewApp creates a new instance of v1alpha1.App based on the provided parameters.
@@ -37,9 +55,15 @@ const (
 func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall, pkgVersion datapkgingv1alpha1.Package, opts Opts) (*v1alpha1.App, error) {
 	desiredApp := existingApp.DeepCopy()
 
-	if _, found := existingApp.Annotations[ManuallyControlledAnnKey]; found {
-		// Skip all updates to App CR if annotation is present
-		return desiredApp, nil
+	var manuallyControlledPaths []string
+	if rawPaths, found := existingApp.Annotations[ManuallyControlledAnnKey]; found {
+		if strings.TrimSpace(rawPaths) == "" {
+			// Skip all updates to App CR if annotation is present with no paths
+			return desiredApp, nil
+		}
+		for _, rawPath := range strings.Split(rawPaths, ",") {
+			manuallyControlledPaths = append(manuallyControlledPaths, strings.TrimSpace(rawPath))
+		}
 	}
 
 	desiredApp.Name = pkgInstall.Name
@@ -51,6 +75,15 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 	desiredApp.Annotations["packaging.carvel.dev/package-ref-name"] = pkgVersion.Spec.RefName
 	desiredApp.Annotations["packaging.carvel.dev/package-version"] = pkgVersion.Spec.Version
 
+	if pkgInstall.Spec.UpgradeConstraintPolicy != pkgingv1alpha1.UpgradeConstraintPolicyIgnore {
+		if installedVersion, found := existingApp.Annotations[InstalledBundleVersionAnnKey]; found {
+			if err := checkUpgradeConstraint(installedVersion, pkgVersion.Spec.Version); err != nil {
+				return &v1alpha1.App{}, err
+			}
+		}
+	}
+	desiredApp.Annotations[InstalledBundleVersionAnnKey] = pkgVersion.Spec.Version
+
 	desiredApp.Spec = *pkgVersion.Spec.Template.Spec
 	desiredApp.Spec.ServiceAccountName = pkgInstall.Spec.ServiceAccountName
 	if pkgInstall.Spec.SyncPeriod == nil {
@@ -113,7 +146,7 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 				if _, found := pkgInstall.Annotations[HelmTemplateOverlayNameSpaceKey]; found {
 					templateStep.HelmTemplate.Namespace = pkgInstall.Annotations[HelmTemplateOverlayNameSpaceKey]
 				}
-				for _, secretName := range secretNamesFromAnn(pkgInstall, ExtHelmPathsFromSecretNameAnnKey) {
+				for _, secretName := range SecretNamesFromAnn(pkgInstall, ExtHelmPathsFromSecretNameAnnKey) {
 					templateStep.HelmTemplate.ValuesFrom = append(templateStep.HelmTemplate.ValuesFrom, kcv1alpha1.AppTemplateValuesSource{
 						SecretRef: &kcv1alpha1.AppTemplateValuesSourceRef{
 							Name: secretName,
@@ -125,11 +158,11 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 				valuesApplied = true
 
 				for _, value := range pkgInstall.Spec.Values {
-					templateStep.HelmTemplate.ValuesFrom = append(templateStep.HelmTemplate.ValuesFrom, kcv1alpha1.AppTemplateValuesSource{
-						SecretRef: &kcv1alpha1.AppTemplateValuesSourceRef{
-							Name: value.SecretRef.Name,
-						},
-					})
+					valuesFrom, err := appTemplateValuesSourceFor(value)
+					if err != nil {
+						return &v1alpha1.App{}, err
+					}
+					templateStep.HelmTemplate.ValuesFrom = append(templateStep.HelmTemplate.ValuesFrom, valuesFrom)
 				}
 			}
 		}
@@ -138,7 +171,7 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 			if !yttPathsApplied {
 				yttPathsApplied = true
 
-				for _, secretName := range secretNamesFromAnn(pkgInstall, ExtYttPathsFromSecretNameAnnKey) {
+				for _, secretName := range SecretNamesFromAnn(pkgInstall, ExtYttPathsFromSecretNameAnnKey) {
 					if templateStep.Ytt.Inline == nil {
 						templateStep.Ytt.Inline = &kcv1alpha1.AppFetchInline{}
 					}
@@ -158,19 +191,19 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 						templateStep.Ytt.Inline = &kcv1alpha1.AppFetchInline{}
 					}
 					for _, value := range pkgInstall.Spec.Values {
-						templateStep.Ytt.Inline.PathsFrom = append(templateStep.Ytt.Inline.PathsFrom, kcv1alpha1.AppFetchInlineSource{
-							SecretRef: &kcv1alpha1.AppFetchInlineSourceRef{
-								Name: value.SecretRef.Name,
-							},
-						})
+						pathsFrom, err := appFetchInlineSourceFor(value)
+						if err != nil {
+							return &v1alpha1.App{}, err
+						}
+						templateStep.Ytt.Inline.PathsFrom = append(templateStep.Ytt.Inline.PathsFrom, pathsFrom)
 					}
 				} else {
 					for _, value := range pkgInstall.Spec.Values {
-						templateStep.Ytt.ValuesFrom = append(templateStep.Ytt.ValuesFrom, kcv1alpha1.AppTemplateValuesSource{
-							SecretRef: &kcv1alpha1.AppTemplateValuesSourceRef{
-								Name: value.SecretRef.Name,
-							},
-						})
+						valuesFrom, err := appTemplateValuesSourceFor(value)
+						if err != nil {
+							return &v1alpha1.App{}, err
+						}
+						templateStep.Ytt.ValuesFrom = append(templateStep.Ytt.ValuesFrom, valuesFrom)
 					}
 				}
 			}
@@ -179,10 +212,63 @@ func NewApp(existingApp *v1alpha1.App, pkgInstall *pkgingv1alpha1.PackageInstall
 		desiredApp.Spec.Template[i] = templateStep
 	}
 
+	for _, rawPath := range manuallyControlledPaths {
+		path, err := pathresolver.Parse(rawPath)
+		if err != nil {
+			return &v1alpha1.App{}, &ManuallyControlledPathError{Path: rawPath, Err: err}
+		}
+		if err := pathresolver.CopyInto(desiredApp, existingApp, path); err != nil {
+			return &v1alpha1.App{}, &ManuallyControlledPathError{Path: rawPath, Err: err}
+		}
+	}
+
 	return desiredApp, nil
 }
 
-func secretNamesFromAnn(installedPkg *pkgingv1alpha1.PackageInstall, annKey string) []string {
+// appTemplateValuesSourceFor converts a PackageInstall value entry into the
+// AppTemplateValuesSource it should produce on the App, requiring exactly
+// one of SecretRef or ConfigMapRef to be set.
+func appTemplateValuesSourceFor(value pkgingv1alpha1.PackageInstallValues) (kcv1alpha1.AppTemplateValuesSource, error) {
+	switch {
+	case value.SecretRef != nil && value.ConfigMapRef == nil:
+		return kcv1alpha1.AppTemplateValuesSource{
+			SecretRef: &kcv1alpha1.AppTemplateValuesSourceRef{Name: value.SecretRef.Name},
+		}, nil
+	case value.ConfigMapRef != nil && value.SecretRef == nil:
+		return kcv1alpha1.AppTemplateValuesSource{
+			ConfigMapRef: &kcv1alpha1.AppTemplateValuesSourceRef{Name: value.ConfigMapRef.Name},
+		}, nil
+	default:
+		return kcv1alpha1.AppTemplateValuesSource{}, fmt.Errorf(
+			"Expected exactly one of spec.values[].secretRef or spec.values[].configMapRef to be set")
+	}
+}
+
+// appFetchInlineSourceFor is the AppFetchInline.PathsFrom equivalent of
+// appTemplateValuesSourceFor, used when values are overlaid as ytt data
+// values files rather than passed via valuesFrom.
+func appFetchInlineSourceFor(value pkgingv1alpha1.PackageInstallValues) (kcv1alpha1.AppFetchInlineSource, error) {
+	switch {
+	case value.SecretRef != nil && value.ConfigMapRef == nil:
+		return kcv1alpha1.AppFetchInlineSource{
+			SecretRef: &kcv1alpha1.AppFetchInlineSourceRef{Name: value.SecretRef.Name},
+		}, nil
+	case value.ConfigMapRef != nil && value.SecretRef == nil:
+		return kcv1alpha1.AppFetchInlineSource{
+			ConfigMapRef: &kcv1alpha1.AppFetchInlineSourceRef{Name: value.ConfigMapRef.Name},
+		}, nil
+	default:
+		return kcv1alpha1.AppFetchInlineSource{}, fmt.Errorf(
+			"Expected exactly one of spec.values[].secretRef or spec.values[].configMapRef to be set")
+	}
+}
+
+// SecretNamesFromAnn returns the secret names recorded under annKey (and
+// any annKey.<suffix> variants) on installedPkg, sorted deterministically
+// by suffix. It is exported so that callers outside this package (e.g. the
+// packagingaggregation read API) can collapse the same ext.* annotations
+// NewApp does, rather than re-implementing the parsing.
+func SecretNamesFromAnn(installedPkg *pkgingv1alpha1.PackageInstall, annKey string) []string {
 	var suffixes []string
 	suffixToSecretName := map[string]string{}
 
@@ -206,3 +292,38 @@ func secretNamesFromAnn(installedPkg *pkgingv1alpha1.PackageInstall, annKey stri
 	}
 	return result
 }
+
+var extFetchSecretNameAnnKeyRegexp = regexp.MustCompile(`^ext\.packaging\.carvel\.dev/fetch-(\d+)-secret-name$`)
+
+// FetchSecretNamesFromAnn returns the secret names recorded via
+// ExtFetchSecretNameAnnKeyFmt annotations on installedPkg, ordered by
+// their fetch step index. It is exported for the same reason
+// SecretNamesFromAnn is: so callers outside this package can collapse the
+// fetch-secret-ref annotation model NewApp uses instead of re-parsing it.
+func FetchSecretNamesFromAnn(installedPkg *pkgingv1alpha1.PackageInstall) []string {
+	type indexedSecretName struct {
+		index      int
+		secretName string
+	}
+
+	var indexed []indexedSecretName
+	for ann, secretName := range installedPkg.Annotations {
+		match := extFetchSecretNameAnnKeyRegexp.FindStringSubmatch(ann)
+		if match == nil {
+			continue
+		}
+		index, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		indexed = append(indexed, indexedSecretName{index: index, secretName: secretName})
+	}
+
+	sort.Slice(indexed, func(i, j int) bool { return indexed[i].index < indexed[j].index })
+
+	result := make([]string, 0, len(indexed))
+	for _, entry := range indexed {
+		result = append(result, entry.secretName)
+	}
+	return result
+}
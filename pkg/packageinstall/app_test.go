@@ -0,0 +1,344 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packageinstall_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"carvel.dev/kapp-controller/pkg/apis/kappctrl/v1alpha1"
+	pkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apis/packaging/v1alpha1"
+	datapkgingv1alpha1 "carvel.dev/kapp-controller/pkg/apiserver/apis/datapackaging/v1alpha1"
+	"carvel.dev/kapp-controller/pkg/packageinstall"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAppUpgradeConstraintPolicy(t *testing.T) {
+	opts := packageinstall.Opts{DefaultSyncPeriod: 10 * time.Minute}
+
+	pkgInstallWithVersion := func(policy pkgingv1alpha1.UpgradeConstraintPolicy) *pkgingv1alpha1.PackageInstall {
+		return &pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1"},
+			Spec:       pkgingv1alpha1.PackageInstallSpec{UpgradeConstraintPolicy: policy},
+		}
+	}
+
+	pkgVersion := func(version string) datapkgingv1alpha1.Package {
+		return datapkgingv1alpha1.Package{
+			Spec: datapkgingv1alpha1.PackageSpec{
+				RefName: "pkg.test.carvel.dev",
+				Version: version,
+				Template: datapkgingv1alpha1.AppTemplateSpec{
+					Spec: &v1alpha1.AppSpec{},
+				},
+			},
+		}
+	}
+
+	existingAppWithInstalledVersion := func(version string) *v1alpha1.App {
+		app := &v1alpha1.App{}
+		if version != "" {
+			app.Annotations = map[string]string{packageinstall.InstalledBundleVersionAnnKey: version}
+		}
+		return app
+	}
+
+	t.Run("fresh install records the resolved version without a prior annotation", func(t *testing.T) {
+		app, err := packageinstall.NewApp(existingAppWithInstalledVersion(""), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("1.0.0"), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if v := app.Annotations[packageinstall.InstalledBundleVersionAnnKey]; v != "1.0.0" {
+			t.Fatalf("expected installed version annotation '1.0.0', got '%s'", v)
+		}
+	})
+
+	t.Run("equal versions are allowed", func(t *testing.T) {
+		app, err := packageinstall.NewApp(existingAppWithInstalledVersion("1.0.0"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("1.0.0"), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if v := app.Annotations[packageinstall.InstalledBundleVersionAnnKey]; v != "1.0.0" {
+			t.Fatalf("expected installed version annotation '1.0.0', got '%s'", v)
+		}
+	})
+
+	t.Run("upgrades are allowed", func(t *testing.T) {
+		app, err := packageinstall.NewApp(existingAppWithInstalledVersion("1.0.0"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("2.0.0"), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if v := app.Annotations[packageinstall.InstalledBundleVersionAnnKey]; v != "2.0.0" {
+			t.Fatalf("expected installed version annotation '2.0.0', got '%s'", v)
+		}
+	})
+
+	t.Run("downgrades are blocked when policy is Enforce", func(t *testing.T) {
+		_, err := packageinstall.NewApp(existingAppWithInstalledVersion("2.0.0"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("1.0.0"), opts)
+		if err == nil {
+			t.Fatalf("expected a DowngradeBlockedError, got none")
+		}
+		var downgradeErr *packageinstall.DowngradeBlockedError
+		if !errors.As(err, &downgradeErr) {
+			t.Fatalf("expected a *DowngradeBlockedError, got: %T (%s)", err, err)
+		}
+	})
+
+	t.Run("downgrades are allowed when policy is Ignore", func(t *testing.T) {
+		app, err := packageinstall.NewApp(existingAppWithInstalledVersion("2.0.0"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyIgnore), pkgVersion("1.0.0"), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if v := app.Annotations[packageinstall.InstalledBundleVersionAnnKey]; v != "1.0.0" {
+			t.Fatalf("expected installed version annotation '1.0.0', got '%s'", v)
+		}
+	})
+
+	t.Run("malformed installed version surfaces a parse error", func(t *testing.T) {
+		_, err := packageinstall.NewApp(existingAppWithInstalledVersion("not-a-version"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("1.0.0"), opts)
+		if err == nil {
+			t.Fatalf("expected a parse error, got none")
+		}
+		var downgradeErr *packageinstall.DowngradeBlockedError
+		if errors.As(err, &downgradeErr) {
+			t.Fatalf("expected a parse error, not a DowngradeBlockedError")
+		}
+	})
+
+	t.Run("malformed resolved version surfaces a parse error", func(t *testing.T) {
+		_, err := packageinstall.NewApp(existingAppWithInstalledVersion("1.0.0"), pkgInstallWithVersion(pkgingv1alpha1.UpgradeConstraintPolicyEnforce), pkgVersion("not-a-version"), opts)
+		if err == nil {
+			t.Fatalf("expected a parse error, got none")
+		}
+	})
+}
+
+func TestNewAppValuesConfigMapRef(t *testing.T) {
+	opts := packageinstall.Opts{DefaultSyncPeriod: 10 * time.Minute}
+
+	pkgVersion := func(template v1alpha1.AppTemplate) datapkgingv1alpha1.Package {
+		return datapkgingv1alpha1.Package{
+			Spec: datapkgingv1alpha1.PackageSpec{
+				RefName: "pkg.test.carvel.dev",
+				Version: "1.0.0",
+				Template: datapkgingv1alpha1.AppTemplateSpec{
+					Spec: &v1alpha1.AppSpec{Template: []v1alpha1.AppTemplate{template}},
+				},
+			},
+		}
+	}
+
+	t.Run("helmTemplate passes through a ConfigMapRef value", func(t *testing.T) {
+		pkgInstall := &pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1"},
+			Spec: pkgingv1alpha1.PackageInstallSpec{
+				Values: []pkgingv1alpha1.PackageInstallValues{{
+					ConfigMapRef: &pkgingv1alpha1.PackageInstallValuesConfigMapRef{Name: "cm1"},
+				}},
+			},
+		}
+
+		app, err := packageinstall.NewApp(&v1alpha1.App{}, pkgInstall, pkgVersion(v1alpha1.AppTemplate{
+			HelmTemplate: &v1alpha1.AppTemplateHelmTemplate{},
+		}), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		valuesFrom := app.Spec.Template[0].HelmTemplate.ValuesFrom
+		if len(valuesFrom) != 1 || valuesFrom[0].ConfigMapRef == nil || valuesFrom[0].ConfigMapRef.Name != "cm1" {
+			t.Fatalf("expected a single configMapRef valuesFrom entry for 'cm1', got: %+v", valuesFrom)
+		}
+	})
+
+	t.Run("ytt passes through a ConfigMapRef value", func(t *testing.T) {
+		pkgInstall := &pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1"},
+			Spec: pkgingv1alpha1.PackageInstallSpec{
+				Values: []pkgingv1alpha1.PackageInstallValues{{
+					ConfigMapRef: &pkgingv1alpha1.PackageInstallValuesConfigMapRef{Name: "cm1"},
+				}},
+			},
+		}
+
+		app, err := packageinstall.NewApp(&v1alpha1.App{}, pkgInstall, pkgVersion(v1alpha1.AppTemplate{
+			Ytt: &v1alpha1.AppTemplateYtt{},
+		}), opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		valuesFrom := app.Spec.Template[0].Ytt.ValuesFrom
+		if len(valuesFrom) != 1 || valuesFrom[0].ConfigMapRef == nil || valuesFrom[0].ConfigMapRef.Name != "cm1" {
+			t.Fatalf("expected a single configMapRef valuesFrom entry for 'cm1', got: %+v", valuesFrom)
+		}
+	})
+
+	t.Run("rejects a value with neither secretRef nor configMapRef set", func(t *testing.T) {
+		pkgInstall := &pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1"},
+			Spec: pkgingv1alpha1.PackageInstallSpec{
+				Values: []pkgingv1alpha1.PackageInstallValues{{}},
+			},
+		}
+
+		_, err := packageinstall.NewApp(&v1alpha1.App{}, pkgInstall, pkgVersion(v1alpha1.AppTemplate{
+			HelmTemplate: &v1alpha1.AppTemplateHelmTemplate{},
+		}), opts)
+		if err == nil {
+			t.Fatalf("expected a validation error, got none")
+		}
+	})
+
+	t.Run("rejects a value with both secretRef and configMapRef set", func(t *testing.T) {
+		pkgInstall := &pkgingv1alpha1.PackageInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1"},
+			Spec: pkgingv1alpha1.PackageInstallSpec{
+				Values: []pkgingv1alpha1.PackageInstallValues{{
+					SecretRef:    &pkgingv1alpha1.PackageInstallValuesSecretRef{Name: "s1"},
+					ConfigMapRef: &pkgingv1alpha1.PackageInstallValuesConfigMapRef{Name: "cm1"},
+				}},
+			},
+		}
+
+		_, err := packageinstall.NewApp(&v1alpha1.App{}, pkgInstall, pkgVersion(v1alpha1.AppTemplate{
+			HelmTemplate: &v1alpha1.AppTemplateHelmTemplate{},
+		}), opts)
+		if err == nil {
+			t.Fatalf("expected a validation error, got none")
+		}
+	})
+}
+
+func TestNewAppManuallyControlledPaths(t *testing.T) {
+	opts := packageinstall.Opts{DefaultSyncPeriod: 10 * time.Minute}
+
+	pkgInstall := &pkgingv1alpha1.PackageInstall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app1",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				fmt.Sprintf(packageinstall.ExtFetchSecretNameAnnKeyFmt, 0): "new-secret",
+			},
+		},
+		Spec: pkgingv1alpha1.PackageInstallSpec{ServiceAccountName: "new-sa"},
+	}
+
+	pkgVersion := datapkgingv1alpha1.Package{
+		Spec: datapkgingv1alpha1.PackageSpec{
+			RefName: "pkg.test.carvel.dev",
+			Version: "1.0.0",
+			Template: datapkgingv1alpha1.AppTemplateSpec{
+				Spec: &v1alpha1.AppSpec{
+					Fetch:    []v1alpha1.AppFetch{{Image: &v1alpha1.AppFetchImage{URL: "registry.test/image"}}},
+					Template: []v1alpha1.AppTemplate{{Ytt: &v1alpha1.AppTemplateYtt{Paths: []string{"/new-path"}}}},
+				},
+			},
+		},
+	}
+
+	existingApp := func() *v1alpha1.App {
+		return &v1alpha1.App{
+			Spec: v1alpha1.AppSpec{
+				ServiceAccountName: "old-sa",
+				Fetch:              []v1alpha1.AppFetch{{Image: &v1alpha1.AppFetchImage{URL: "registry.test/image", SecretRef: &v1alpha1.AppFetchLocalRef{Name: "old-secret"}}}},
+				Template:           []v1alpha1.AppTemplate{{Ytt: &v1alpha1.AppTemplateYtt{Paths: []string{"/old-path"}}}},
+			},
+		}
+	}
+
+	t.Run("empty annotation value keeps freezing every field", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{packageinstall.ManuallyControlledAnnKey: ""}
+
+		app, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if app.Spec.ServiceAccountName != "old-sa" {
+			t.Fatalf("expected the App to be left untouched, got: %+v", app.Spec)
+		}
+	})
+
+	t.Run("top-level scalar path round-trips", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{packageinstall.ManuallyControlledAnnKey: "spec.serviceAccountName"}
+
+		app, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if app.Spec.ServiceAccountName != "old-sa" {
+			t.Errorf("expected spec.serviceAccountName to be restored to 'old-sa', got '%s'", app.Spec.ServiceAccountName)
+		}
+	})
+
+	t.Run("fetch-index path round-trips", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{packageinstall.ManuallyControlledAnnKey: "spec.fetch[0].image.secretRef"}
+
+		app, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if app.Spec.Fetch[0].Image.SecretRef == nil || app.Spec.Fetch[0].Image.SecretRef.Name != "old-secret" {
+			t.Errorf("expected spec.fetch[0].image.secretRef to be restored to 'old-secret', got: %+v", app.Spec.Fetch[0].Image.SecretRef)
+		}
+		// Fields not named in the annotation should still reconcile normally.
+		if app.Spec.ServiceAccountName != "new-sa" {
+			t.Errorf("expected spec.serviceAccountName to reconcile to 'new-sa', got '%s'", app.Spec.ServiceAccountName)
+		}
+	})
+
+	t.Run("template-index path round-trips", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{packageinstall.ManuallyControlledAnnKey: "spec.template[0].ytt.paths"}
+
+		app, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if len(app.Spec.Template[0].Ytt.Paths) != 1 || app.Spec.Template[0].Ytt.Paths[0] != "/old-path" {
+			t.Errorf("expected spec.template[0].ytt.paths to be restored to ['/old-path'], got: %+v", app.Spec.Template[0].Ytt.Paths)
+		}
+		// Fields not named in the annotation should still reconcile normally.
+		if app.Spec.Fetch[0].Image.SecretRef == nil || app.Spec.Fetch[0].Image.SecretRef.Name != "new-secret" {
+			t.Errorf("expected spec.fetch[0].image.secretRef to reconcile to 'new-secret', got: %+v", app.Spec.Fetch[0].Image.SecretRef)
+		}
+	})
+
+	t.Run("multiple comma-separated paths all round-trip", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{
+			packageinstall.ManuallyControlledAnnKey: "spec.serviceAccountName, spec.fetch[0].image.secretRef",
+		}
+
+		app, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if app.Spec.ServiceAccountName != "old-sa" {
+			t.Errorf("expected spec.serviceAccountName to be restored to 'old-sa', got '%s'", app.Spec.ServiceAccountName)
+		}
+		if app.Spec.Fetch[0].Image.SecretRef == nil || app.Spec.Fetch[0].Image.SecretRef.Name != "old-secret" {
+			t.Errorf("expected spec.fetch[0].image.secretRef to be restored to 'old-secret', got: %+v", app.Spec.Fetch[0].Image.SecretRef)
+		}
+	})
+
+	t.Run("unknown path surfaces a validation error", func(t *testing.T) {
+		existing := existingApp()
+		existing.Annotations = map[string]string{packageinstall.ManuallyControlledAnnKey: "spec.doesNotExist"}
+
+		_, err := packageinstall.NewApp(existing, pkgInstall, pkgVersion, opts)
+		if err == nil {
+			t.Fatalf("expected a validation error, got none")
+		}
+		var pathErr *packageinstall.ManuallyControlledPathError
+		if !errors.As(err, &pathErr) {
+			t.Fatalf("expected a *ManuallyControlledPathError, got: %T (%s)", err, err)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packageinstall
+
+import "fmt"
+
+// ManuallyControlledPathError is returned by NewApp when a path named in
+// the ManuallyControlledAnnKey annotation cannot be resolved against
+// the App, so the controller can surface a validation error on the
+// PackageInstall status instead of silently no-oping on the bad path.
+type ManuallyControlledPathError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ManuallyControlledPathError) Error() string {
+	return fmt.Sprintf("Resolving path '%s' in annotation '%s': %s", e.Path, ManuallyControlledAnnKey, e.Err)
+}
+
+// Unwrap allows errors.Is/errors.As to see the underlying resolution error.
+func (e *ManuallyControlledPathError) Unwrap() error {
+	return e.Err
+}
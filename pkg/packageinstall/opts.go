@@ -0,0 +1,12 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packageinstall
+
+import "time"
+
+// Opts controls defaults used by NewApp when a PackageInstall does not
+// specify a value explicitly.
+type Opts struct {
+	DefaultSyncPeriod time.Duration
+}
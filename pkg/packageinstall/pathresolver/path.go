@@ -0,0 +1,155 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pathresolver resolves small, dotted/indexed JSONPath-like
+// strings (e.g. "spec.fetch[0].image.secretRef") against the JSON tags of
+// a struct, so that callers can restore individual fields of one value
+// from another without hand-rolling a switch per field.
+package pathresolver
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Segment is one step of a parsed Path: a struct field addressed by its
+// JSON tag name, optionally followed by a slice index.
+type Segment struct {
+	Field    string
+	Index    int
+	HasIndex bool
+}
+
+// Path is a parsed dotted/indexed field path.
+type Path []Segment
+
+var segmentRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9]*)(\[(\d+)\])?$`)
+
+// Parse parses a dotted/indexed path such as "spec.fetch[0].image.secretRef"
+// into a Path. It returns an error if any segment is malformed.
+func Parse(raw string) (Path, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("Path must not be empty")
+	}
+
+	parts := strings.Split(raw, ".")
+	path := make(Path, 0, len(parts))
+
+	for _, part := range parts {
+		match := segmentRegexp.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("Invalid path segment '%s' in '%s'", part, raw)
+		}
+
+		seg := Segment{Field: match[1]}
+		if match[3] != "" {
+			index, err := strconv.Atoi(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid index in path segment '%s' in '%s'", part, raw)
+			}
+			seg.Index = index
+			seg.HasIndex = true
+		}
+
+		path = append(path, seg)
+	}
+
+	return path, nil
+}
+
+// CopyInto copies the value found at path within src onto the same path
+// within dst. dst and src must be non-nil pointers to the same struct
+// type. It returns an error if the path does not resolve to an existing,
+// addressable field on either side (e.g. an unknown field name, an
+// out-of-range slice index, or a path through a nil pointer in src).
+func CopyInto(dst, src interface{}, path Path) error {
+	if len(path) == 0 {
+		return fmt.Errorf("Path must not be empty")
+	}
+
+	dstVal := reflect.ValueOf(dst)
+	srcVal := reflect.ValueOf(src)
+	if dstVal.Kind() != reflect.Ptr || srcVal.Kind() != reflect.Ptr || dstVal.IsNil() || srcVal.IsNil() {
+		return fmt.Errorf("CopyInto requires non-nil pointers")
+	}
+
+	return copyInto(dstVal.Elem(), srcVal.Elem(), path)
+}
+
+func copyInto(dst, src reflect.Value, path Path) error {
+	seg := path[0]
+
+	dstField, err := fieldByJSONName(dst, seg.Field)
+	if err != nil {
+		return err
+	}
+	srcField, err := fieldByJSONName(src, seg.Field)
+	if err != nil {
+		return err
+	}
+
+	if seg.HasIndex {
+		if dstField.Kind() != reflect.Slice || srcField.Kind() != reflect.Slice {
+			return fmt.Errorf("Field '%s' is not a list", seg.Field)
+		}
+		if seg.Index < 0 || seg.Index >= dstField.Len() || seg.Index >= srcField.Len() {
+			return fmt.Errorf("Index %d is out of range for field '%s'", seg.Index, seg.Field)
+		}
+		dstField = dstField.Index(seg.Index)
+		srcField = srcField.Index(seg.Index)
+	}
+
+	if len(path) == 1 {
+		if !dstField.CanSet() {
+			return fmt.Errorf("Field '%s' cannot be set", seg.Field)
+		}
+		dstField.Set(srcField)
+		return nil
+	}
+
+	dstField, srcField, err = derefForTraversal(dstField, srcField, seg.Field)
+	if err != nil {
+		return err
+	}
+
+	return copyInto(dstField, srcField, path[1:])
+}
+
+// derefForTraversal prepares a pair of (possibly pointer) fields for
+// further traversal: if src is a nil pointer there's nothing to copy, so
+// traversal fails; if dst is a nil pointer it is allocated so the copy has
+// somewhere to land.
+func derefForTraversal(dst, src reflect.Value, fieldName string) (reflect.Value, reflect.Value, error) {
+	if dst.Kind() != reflect.Ptr {
+		return dst, src, nil
+	}
+
+	if src.IsNil() {
+		return reflect.Value{}, reflect.Value{}, fmt.Errorf("Field '%s' is not set on the source value", fieldName)
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.New(dst.Type().Elem()))
+	}
+
+	return dst.Elem(), src.Elem(), nil
+}
+
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, error) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("Field '%s' is not a struct", name)
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		tagName := strings.Split(tag, ",")[0]
+		if tagName == name {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("Unknown field '%s'", name)
+}
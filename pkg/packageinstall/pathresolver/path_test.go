@@ -0,0 +1,164 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package pathresolver_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp-controller/pkg/packageinstall/pathresolver"
+)
+
+type innerType struct {
+	Name string `json:"name,omitempty"`
+}
+
+type itemType struct {
+	Inner *innerType `json:"inner,omitempty"`
+}
+
+type rootType struct {
+	Scalar string     `json:"scalar,omitempty"`
+	Items  []itemType `json:"items,omitempty"`
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		raw     string
+		want    pathresolver.Path
+		wantErr bool
+	}{
+		{raw: "scalar", want: pathresolver.Path{{Field: "scalar"}}},
+		{raw: "items[0].inner.name", want: pathresolver.Path{
+			{Field: "items", Index: 0, HasIndex: true},
+			{Field: "inner"},
+			{Field: "name"},
+		}},
+		{raw: "", wantErr: true},
+		{raw: "items[abc]", wantErr: true},
+		{raw: "items[0", wantErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := pathresolver.Parse(c.raw)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q): expected error, got none", c.raw)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.raw, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", c.raw, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("Parse(%q)[%d] = %+v, want %+v", c.raw, i, got[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestCopyIntoTopLevelScalar(t *testing.T) {
+	dst := &rootType{Scalar: "desired"}
+	src := &rootType{Scalar: "existing"}
+
+	path, err := pathresolver.Parse("scalar")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err != nil {
+		t.Fatalf("CopyInto: %s", err)
+	}
+	if dst.Scalar != "existing" {
+		t.Errorf("expected dst.Scalar to be restored to 'existing', got '%s'", dst.Scalar)
+	}
+}
+
+func TestCopyIntoIndexedPointerField(t *testing.T) {
+	dst := &rootType{Items: []itemType{{Inner: &innerType{Name: "desired"}}}}
+	src := &rootType{Items: []itemType{{Inner: &innerType{Name: "existing"}}}}
+
+	path, err := pathresolver.Parse("items[0].inner.name")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err != nil {
+		t.Fatalf("CopyInto: %s", err)
+	}
+	if dst.Items[0].Inner.Name != "existing" {
+		t.Errorf("expected dst.Items[0].Inner.Name to be restored to 'existing', got '%s'", dst.Items[0].Inner.Name)
+	}
+}
+
+func TestCopyIntoAllocatesNilDestinationPointers(t *testing.T) {
+	dst := &rootType{Items: []itemType{{Inner: nil}}}
+	src := &rootType{Items: []itemType{{Inner: &innerType{Name: "existing"}}}}
+
+	path, err := pathresolver.Parse("items[0].inner.name")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err != nil {
+		t.Fatalf("CopyInto: %s", err)
+	}
+	if dst.Items[0].Inner == nil || dst.Items[0].Inner.Name != "existing" {
+		t.Errorf("expected dst.Items[0].Inner to be allocated and restored, got %+v", dst.Items[0].Inner)
+	}
+}
+
+func TestCopyIntoUnknownFieldErrors(t *testing.T) {
+	dst := &rootType{}
+	src := &rootType{}
+
+	path, err := pathresolver.Parse("doesNotExist")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err == nil {
+		t.Fatalf("expected an error for an unknown field, got none")
+	}
+}
+
+func TestCopyIntoOutOfRangeIndexErrors(t *testing.T) {
+	dst := &rootType{Items: []itemType{{}}}
+	src := &rootType{Items: []itemType{{}}}
+
+	path, err := pathresolver.Parse("items[5].inner.name")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err == nil {
+		t.Fatalf("expected an error for an out-of-range index, got none")
+	}
+}
+
+func TestCopyIntoTraversalThroughScalarErrors(t *testing.T) {
+	dst := &rootType{}
+	src := &rootType{}
+
+	path, err := pathresolver.Parse("scalar.foo")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err == nil {
+		t.Fatalf("expected an error when traversing through a scalar field, got none")
+	}
+}
+
+func TestCopyIntoNilSourcePointerErrors(t *testing.T) {
+	dst := &rootType{Items: []itemType{{Inner: &innerType{Name: "desired"}}}}
+	src := &rootType{Items: []itemType{{Inner: nil}}}
+
+	path, err := pathresolver.Parse("items[0].inner.name")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if err := pathresolver.CopyInto(dst, src, path); err == nil {
+		t.Fatalf("expected an error when traversing through a nil source pointer, got none")
+	}
+}
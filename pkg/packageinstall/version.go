@@ -0,0 +1,46 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package packageinstall
+
+import (
+	"fmt"
+
+	semver "github.com/k14s/semver/v4"
+)
+
+// DowngradeBlockedError is returned by NewApp when a PackageInstall's
+// spec.upgradeConstraintPolicy is Enforce (the default) and the resolved
+// package version is older than the version already installed.
+type DowngradeBlockedError struct {
+	InstalledVersion string
+	RequestedVersion string
+}
+
+// Error implements the error interface.
+func (e *DowngradeBlockedError) Error() string {
+	return fmt.Sprintf("Downgrading from version '%s' to '%s' is not allowed "+
+		"(spec.upgradeConstraintPolicy is set to Enforce)", e.InstalledVersion, e.RequestedVersion)
+}
+
+// checkUpgradeConstraint returns a *DowngradeBlockedError if requestedVersion
+// is strictly lower than installedVersion. Both versions must be valid
+// semver strings, otherwise an error describing the parse failure is
+// returned.
+func checkUpgradeConstraint(installedVersion, requestedVersion string) error {
+	installedSemver, err := semver.Parse(installedVersion)
+	if err != nil {
+		return fmt.Errorf("Parsing installed bundle version '%s': %s", installedVersion, err)
+	}
+
+	requestedSemver, err := semver.Parse(requestedVersion)
+	if err != nil {
+		return fmt.Errorf("Parsing resolved package version '%s': %s", requestedVersion, err)
+	}
+
+	if requestedSemver.LT(installedSemver) {
+		return &DowngradeBlockedError{InstalledVersion: installedVersion, RequestedVersion: requestedVersion}
+	}
+
+	return nil
+}